@@ -1,52 +1,635 @@
 package main // Define the main package
 
 import (
-	"bytes"         // Provides bytes support
+	"context"       // Provides cancellation signals shared across a crawl
+	"crypto/sha256" // Provides SHA-256 checksums for verifying assembled downloads and detecting repeated pages
+	"encoding/hex"  // Provides hex encoding for checksum output
+	"encoding/json" // Provides JSON encoding for the resumable download state file and JSON log lines
+	"encoding/xml"  // Provides streaming XML token decoding for sitemap discovery
+	"errors"        // Provides error wrapping/inspection for retry classification
+	"flag"          // Provides command-line flag parsing
 	"fmt"           // Provides formatted I/O functions
 	"io"            // Provides basic interfaces to I/O primitives
 	"log"           // Provides logging functions
+	"math/rand"     // Provides jitter for the retry backoff helper
+	"net"           // Provides network error types for retry classification
 	"net/http"      // Provides HTTP client and server implementations
 	"net/url"       // Provides URL parsing and encoding
 	"os"            // Provides functions to interact with the OS (files, etc.)
 	"path"          // Provides functions for manipulating slash-separated paths
 	"path/filepath" // Provides filepath manipulation functions
 	"regexp"        // Provides regular expression matching
+	"sort"          // Provides sorting of a batch's out-of-order results
+	"strconv"       // Provides string-to-number conversions
 	"strings"       // Provides string manipulation functions
 	"sync"          // Provides synchronization primitives (like WaitGroup)
+	"sync/atomic"   // Provides lock-free counters for the concurrent download pool
+	"syscall"       // Provides OS-level connection error constants for retry classification
 	"time"          // Provides time-related functions
 
 	"github.com/PuerkitoBio/goquery" // External package to parse and manipulate HTML
 )
 
+// rangeChunkCount is the number of concurrent byte-range requests used to
+// fetch a single large PDF when the server advertises range support.
+const rangeChunkCount = 6
+
+// rangeMinSize is the smallest Content-Length for which a segmented,
+// range-based download is worthwhile. Smaller files fall back to the
+// plain single-stream path.
+const rangeMinSize = 8 * 1024 * 1024 // 8 MiB
+
+// logChecksums controls whether the assembled file's SHA-256 is computed
+// and logged after a segmented download completes. Hashing a large PDF
+// costs time, so this can be left on by default since the corpus is
+// downloaded once and the cost is amortized.
+const logChecksums = true
+
+// CrawlStrategy selects how a Crawler decides that it has reached the end
+// of the paginated SDS listing and should stop requesting further pages.
+type CrawlStrategy int
+
+const (
+	// StrategyFixedRange crawls pages 0 through MaxPages unconditionally.
+	StrategyFixedRange CrawlStrategy = iota
+
+	// StrategyHashConvergence stops once a page's body hashes the same
+	// as the immediately preceding page — the signature of a server-side
+	// cursor that has run out of new results and started repeating
+	// its last page.
+	StrategyHashConvergence
+
+	// StrategyNoNewLinks stops once a page contributes zero PDF links
+	// that haven't already been seen on an earlier page.
+	StrategyNoNewLinks
+
+	// StrategyNotFound stops at the first page that responds with HTTP 404.
+	StrategyNotFound
+)
+
+// String renders a CrawlStrategy the way it appears in -crawl-strategy.
+func (s CrawlStrategy) String() string {
+	switch s {
+	case StrategyFixedRange:
+		return "fixed-range"
+	case StrategyHashConvergence:
+		return "hash-convergence"
+	case StrategyNoNewLinks:
+		return "no-new-links"
+	case StrategyNotFound:
+		return "not-found"
+	default:
+		return "unknown"
+	}
+}
+
+// parseCrawlStrategy maps the -crawl-strategy flag value to a
+// CrawlStrategy, defaulting to StrategyHashConvergence for an empty or
+// unrecognized value.
+func parseCrawlStrategy(value string) CrawlStrategy {
+	switch strings.ToLower(value) {
+	case "fixed-range":
+		return StrategyFixedRange
+	case "no-new-links":
+		return StrategyNoNewLinks
+	case "not-found":
+		return StrategyNotFound
+	default:
+		return StrategyHashConvergence
+	}
+}
+
+// LogLevel orders the severities a Logger can be asked to emit. Messages
+// below the configured level are dropped.
+type LogLevel int
+
+// Logger severities, lowest to highest.
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String renders a LogLevel the way it appears on the wire/console.
+func (level LogLevel) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// parseLogLevel converts a -log-level flag value into a LogLevel, falling
+// back to LevelInfo for anything unrecognized.
+func parseLogLevel(value string) LogLevel {
+	switch strings.ToLower(value) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// Field is one structured key-value pair attached to a log line, e.g.
+// f("url", finalURL) or f("bytes", written).
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// f is a short constructor for a Field, kept terse since call sites chain
+// several of these per log line.
+func f(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger is implemented by each supported log backend. Callers build up
+// structured context as Fields rather than formatting it into the message
+// string, so the same call site works for both the text and JSON backends.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+}
+
+// appLogger is the process-wide Logger, selected in main from the
+// -log-format and -log-level flags. It defaults to a plain-text, info-level
+// logger so the package behaves sensibly if something logs before main
+// finishes parsing flags.
+var appLogger Logger = newTextLogger(LevelInfo)
+
+// textLogger renders log lines as plain text, preserving today's console
+// output: "LEVEL message key=value key=value ...".
+type textLogger struct {
+	level LogLevel
+}
+
+// newTextLogger builds a textLogger that emits level and above.
+func newTextLogger(level LogLevel) *textLogger {
+	return &textLogger{level: level}
+}
+
+func (t *textLogger) emit(level LogLevel, msg string, fields []Field) {
+	if level < t.level {
+		return
+	}
+
+	var line strings.Builder
+	line.WriteString(strings.ToUpper(level.String()))
+	line.WriteString(" ")
+	line.WriteString(msg)
+	for _, field := range fields {
+		fmt.Fprintf(&line, " %s=%v", field.Key, field.Value)
+	}
+
+	log.Println(line.String())
+}
+
+func (t *textLogger) Debug(msg string, fields ...Field) { t.emit(LevelDebug, msg, fields) }
+func (t *textLogger) Info(msg string, fields ...Field)  { t.emit(LevelInfo, msg, fields) }
+func (t *textLogger) Warn(msg string, fields ...Field)  { t.emit(LevelWarn, msg, fields) }
+func (t *textLogger) Error(msg string, fields ...Field) { t.emit(LevelError, msg, fields) }
+
+// jsonLogger renders each log line as a single JSON object, suitable for
+// ingestion into a log aggregator.
+type jsonLogger struct {
+	level LogLevel
+}
+
+// newJSONLogger builds a jsonLogger that emits level and above.
+func newJSONLogger(level LogLevel) *jsonLogger {
+	return &jsonLogger{level: level}
+}
+
+func (j *jsonLogger) emit(level LogLevel, msg string, fields []Field) {
+	if level < j.level {
+		return
+	}
+
+	entry := make(map[string]interface{}, len(fields)+2)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+	for _, field := range fields {
+		entry[field.Key] = field.Value
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	log.Println(string(line))
+}
+
+func (j *jsonLogger) Debug(msg string, fields ...Field) { j.emit(LevelDebug, msg, fields) }
+func (j *jsonLogger) Info(msg string, fields ...Field)  { j.emit(LevelInfo, msg, fields) }
+func (j *jsonLogger) Warn(msg string, fields ...Field)  { j.emit(LevelWarn, msg, fields) }
+func (j *jsonLogger) Error(msg string, fields ...Field) { j.emit(LevelError, msg, fields) }
+
+// retryConfig controls the exponential-backoff-with-jitter retry helper
+// shared by both the HTML page fetcher and the PDF downloader.
+type retryConfig struct {
+	MaxRetries int           // Number of retries after the first attempt; 0 disables retrying.
+	BaseDelay  time.Duration // Delay before the first retry; doubles every subsequent attempt.
+	MaxDelay   time.Duration // Ceiling applied after doubling.
+	Jitter     time.Duration // Upper bound of a random delay added on top of the backoff.
+}
+
+// defaultRetryConfig builds a retryConfig from the -max-retries flag,
+// using a "polite but resilient" base delay and ceiling.
+func defaultRetryConfig(maxRetries int) retryConfig {
+	return retryConfig{
+		MaxRetries: maxRetries,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+		Jitter:     250 * time.Millisecond,
+	}
+}
+
+// errInvalidContentType and errEmptyDownload mark permanent PDF download
+// failures that a retry would not fix.
+var (
+	errInvalidContentType = errors.New("invalid content type")
+	errEmptyDownload      = errors.New("downloaded 0 bytes")
+)
+
+// httpStatusError captures a non-2xx HTTP response for retry
+// classification, along with any server-supplied Retry-After delay.
+type httpStatusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d", e.status)
+}
+
+// isTransientHTTPStatus reports whether an HTTP status represents a
+// failure worth retrying — rate limiting or a server error — rather than
+// a permanent rejection such as 404 or 401.
+func isTransientHTTPStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// isTransientError reports whether err looks like a transient failure
+// (connection reset, temporary DNS failure, unexpected EOF, a transient
+// HTTP status) worth retrying, as opposed to a permanent one.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return isTransientHTTPStatus(statusErr.status)
+	}
+
+	if errors.Is(err, errInvalidContentType) || errors.Is(err, errEmptyDownload) {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ETIMEDOUT) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
+
+// retryAfterFromError extracts a server-supplied Retry-After delay from
+// err, or 0 if there isn't one.
+func retryAfterFromError(err error) time.Duration {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses a response's Retry-After header (seconds or
+// HTTP-date form) into a duration, returning 0 if absent or unparseable.
+func parseRetryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// retryWithBackoff calls attempt up to cfg.MaxRetries+1 times. attempt
+// reports whether its failure was transient; retryWithBackoff stops
+// immediately on a permanent failure or once retries are exhausted, and
+// otherwise waits base*2^n (capped at MaxDelay) plus jitter — or the
+// server's own Retry-After, when given — before the next try.
+func retryWithBackoff(ctx context.Context, cfg retryConfig, label string, attempt func(attemptNumber int) (retryAfter time.Duration, transient bool, err error)) error {
+	var lastErr error
+
+	for attemptNumber := 0; attemptNumber <= cfg.MaxRetries; attemptNumber++ {
+		retryAfter, transient, err := attempt(attemptNumber)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !transient || attemptNumber == cfg.MaxRetries {
+			break
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = cfg.BaseDelay * time.Duration(int64(1)<<uint(attemptNumber))
+			if delay > cfg.MaxDelay {
+				delay = cfg.MaxDelay
+			}
+			if cfg.Jitter > 0 {
+				delay += time.Duration(rand.Int63n(int64(cfg.Jitter) + 1))
+			}
+		}
+
+		appLogger.Warn("Retrying after transient failure",
+			f("label", label), f("attempt", attemptNumber+1), f("error", err), f("delay_ms", delay.Milliseconds()))
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return lastErr
+}
+
+// crawlBatchSize is the number of pages fetched concurrently per round. A
+// round must finish before the next one starts so that each strategy can
+// inspect its pages in page-number order before deciding whether to stop.
+const crawlBatchSize = 25
+
+// Crawler drives the paginated fetch of SDS listing pages, persisting each
+// page's body to LocalLocation and deciding — according to Strategy — when
+// the crawl has exhausted the catalog instead of relying on a hardcoded
+// final page number.
+type Crawler struct {
+	BaseURL       string
+	LocalLocation string
+	Strategy      CrawlStrategy
+	MaxPages      int // Upper bound for StrategyFixedRange, and a safety cap for the other strategies.
+	RetryConfig   retryConfig
+
+	client *http.Client
+}
+
+// NewCrawler builds a Crawler ready to run with the given strategy.
+func NewCrawler(baseURL, localLocation string, strategy CrawlStrategy, maxPages int, retryConfig retryConfig) *Crawler {
+	return &Crawler{
+		BaseURL:       baseURL,
+		LocalLocation: localLocation,
+		Strategy:      strategy,
+		MaxPages:      maxPages,
+		RetryConfig:   retryConfig,
+		client:        &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// crawlPageResult captures the outcome of fetching one listing page.
+type crawlPageResult struct {
+	pageNumber int
+	body       string
+	status     int
+	err        error
+}
+
+// Run fetches pages in fixed-size concurrent batches, appending each page's
+// body to c.LocalLocation, and stops as soon as c.Strategy's termination
+// condition is met (or c.MaxPages is reached, whichever comes first).
+func (c *Crawler) Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seenPDFLinks := make(map[string]bool)
+	var previousPageHash [sha256.Size]byte
+	havePreviousPageHash := false
+
+	for pageNumber := 0; pageNumber <= c.MaxPages; {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		batchEnd := pageNumber + crawlBatchSize
+		if batchEnd > c.MaxPages+1 {
+			batchEnd = c.MaxPages + 1
+		}
+
+		results := make([]crawlPageResult, 0, batchEnd-pageNumber)
+		var resultsMu sync.Mutex
+		var batchWaitGroup sync.WaitGroup
+
+		for n := pageNumber; n < batchEnd; n++ {
+			// Delay between request launches to avoid overloading the server.
+			time.Sleep(100 * time.Millisecond)
+
+			batchWaitGroup.Add(1)
+			go func(n int) {
+				defer batchWaitGroup.Done()
+
+				fullURL := fmt.Sprintf("%s%d", c.BaseURL, n)
+				body, status, err := getDataFromURLWithRetry(ctx, c.client, fullURL, c.RetryConfig)
+
+				resultsMu.Lock()
+				results = append(results, crawlPageResult{pageNumber: n, body: body, status: status, err: err})
+				resultsMu.Unlock()
+			}(n)
+		}
+		batchWaitGroup.Wait()
+
+		// Evaluate results in page order so hash/zero-new-link comparisons
+		// are made against the immediately preceding page, not an
+		// arbitrary goroutine completion order.
+		sort.Slice(results, func(i, j int) bool { return results[i].pageNumber < results[j].pageNumber })
+
+		for _, result := range results {
+			if result.err != nil {
+				appLogger.Error("Failed to fetch page", f("page", result.pageNumber), f("error", result.err))
+				continue
+			}
+
+			// A non-200 response (e.g. the 404 StrategyNotFound is
+			// watching for) still returns a body and a nil error, but it's
+			// an error page, not a listing page — only persist real pages.
+			if result.status == http.StatusOK {
+				appendAndWriteToFile(c.LocalLocation, result.body)
+			}
+
+			switch c.Strategy {
+			case StrategyNotFound:
+				if result.status == http.StatusNotFound {
+					appLogger.Info("Crawl stopping", f("reason", "404"), f("page", result.pageNumber))
+					cancel()
+				}
+
+			case StrategyHashConvergence:
+				hash := sha256.Sum256([]byte(result.body))
+				if havePreviousPageHash && hash == previousPageHash {
+					appLogger.Info("Crawl stopping", f("reason", "repeats_previous_page"), f("page", result.pageNumber))
+					cancel()
+				}
+				previousPageHash = hash
+				havePreviousPageHash = true
+
+			case StrategyNoNewLinks:
+				newLinks := 0
+				for _, link := range parseHTML(result.body) {
+					if !seenPDFLinks[link] {
+						seenPDFLinks[link] = true
+						newLinks++
+					}
+				}
+				if newLinks == 0 {
+					appLogger.Info("Crawl stopping", f("reason", "no_new_links"), f("page", result.pageNumber))
+					cancel()
+				}
+			}
+
+			// Pages after the one that triggered the stop are neither
+			// persisted nor evaluated.
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		pageNumber = batchEnd
+	}
+
+	if c.Strategy != StrategyFixedRange && ctx.Err() == nil {
+		// A non-fixed strategy never saw its termination condition and we
+		// only stopped because we ran out of MaxPages — likely a listing
+		// page carrying per-request dynamic content (a nonce, timestamp,
+		// etc.) that keeps every page's hash/link-set looking "new".
+		appLogger.Warn("Crawl reached MaxPages without the strategy triggering", f("strategy", c.Strategy), f("max_pages", c.MaxPages))
+	}
+}
+
 func main() {
+	// -log-format selects the log backend: "text" (default, human-readable
+	// console output) or "json" (one JSON object per line, for ingestion
+	// into log aggregators).
+	logFormat := flag.String("log-format", "text", "log output format: text or json")
+
+	// -log-level sets the minimum severity emitted: debug, info, warn, or error.
+	logLevel := flag.String("log-level", "info", "minimum log level: debug, info, warn, or error")
+
+	// -crawl-strategy selects how the SDS listing crawl decides it has
+	// reached the end: "hash-convergence" (default, stops once a page
+	// repeats the previous page's body), "no-new-links" (stops once a
+	// page yields no unseen PDF links), "not-found" (stops at the first
+	// 404), or "fixed-range" (always crawls the full crawlMaxPages cap).
+	crawlStrategy := flag.String("crawl-strategy", "hash-convergence", "crawl termination strategy: hash-convergence, no-new-links, not-found, or fixed-range")
+
+	// -strategy selects how PDFs are downloaded: "synchronous" (one at a
+	// time, useful for debugging) or "concurrent" (a bounded worker pool).
+	strategy := flag.String("strategy", "concurrent", "PDF download strategy: synchronous or concurrent")
+
+	// -concurrency sets the worker pool size used when -strategy=concurrent.
+	concurrency := flag.Int("concurrency", 8, "number of concurrent PDF download workers when -strategy=concurrent")
+
+	// -discovery selects how PDF URLs are found: "html" (the existing
+	// goquery anchor scan over the saved listing pages) or "sitemap" (a
+	// streamed XML sitemap walk, far cheaper than paginating thousands of
+	// listing pages).
+	discovery := flag.String("discovery", "html", "PDF discovery backend: html or sitemap")
+
+	// -sitemap-url is the sitemap fetched when -discovery=sitemap.
+	sitemapURL := flag.String("sitemap-url", "https://www.avient.com/sitemap.xml", "sitemap URL used when -discovery=sitemap")
+
+	// -verify re-hashes every file manifest.json tracks, reports any
+	// drift or missing file, and exits without downloading anything.
+	verify := flag.Bool("verify", false, "re-hash the PDF corpus against manifest.json and report drift, then exit")
+
+	// -max-retries bounds how many times a single page fetch or PDF
+	// download is retried after its first attempt before giving up,
+	// backing off exponentially (with jitter) between attempts.
+	maxRetries := flag.Int("max-retries", 5, "maximum retry attempts for transient page fetch and PDF download failures")
+
+	flag.Parse()
+
+	retries := defaultRetryConfig(*maxRetries)
+
+	level := parseLogLevel(*logLevel)
+	if strings.EqualFold(*logFormat, "json") {
+		appLogger = newJSONLogger(level)
+	} else {
+		appLogger = newTextLogger(level)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Base URL for paginated Safety Data Sheets (SDS) pages
 	baseURL := "https://www.avient.com/resources/safety-data-sheets?page="
 
 	// Local file name to save all downloaded HTML pages
 	localLocation := "avient.html"
 
-	// WaitGroup used to synchronize multiple concurrent HTML download goroutines
-	var htmlDownloadWaitGroup sync.WaitGroup
-
-	// If the HTML file doesn’t exist locally, start downloading it
-	if !fileExists(localLocation) {
-		// Loop through all paginated SDS pages (0 to 7180)
-		for pageNumber := 0; pageNumber <= 7180; pageNumber++ {
-			// Delay 100 milliseconds between each request to avoid overloading the server
-			time.Sleep(100 * time.Millisecond)
-
-			// Construct the full URL by appending the current page number to the base URL
-			fullURL := fmt.Sprintf("%s%d", baseURL, pageNumber)
+	// crawlMaxPages is a safety cap, not the expected page count: the
+	// hash-convergence strategy below stops as soon as Avient's own
+	// pagination starts repeating itself, so this only guards against an
+	// unexpected server change that never signals the end of the list.
+	const crawlMaxPages = 20000
 
-			// Add one to the WaitGroup counter before starting a new goroutine
-			htmlDownloadWaitGroup.Add(1)
+	// manifest.json sits alongside the PDFs/ directory.
+	manifestPath := "manifest.json"
 
-			// Launch a goroutine to download the HTML page and append it to the local file
-			go getDataFromURL(fullURL, localLocation, &htmlDownloadWaitGroup)
-		}
+	// -verify only re-hashes files already on disk, so it is handled
+	// before the listing crawl below.
+	if *verify {
+		verifyManifest(loadManifest(manifestPath))
+		return
+	}
 
-		// Wait for all goroutines to finish downloading HTML pages
-		htmlDownloadWaitGroup.Wait()
+	// If the HTML file doesn’t exist locally, start downloading it
+	if !fileExists(localLocation) {
+		crawler := NewCrawler(baseURL, localLocation, parseCrawlStrategy(*crawlStrategy), crawlMaxPages, retries)
+		crawler.Run()
 	}
 
 	// Directory to store all downloaded PDF files
@@ -61,82 +644,478 @@ func main() {
 	// Verify that the local HTML file exists before parsing it
 	if !fileExists(localLocation) {
 		// Log a message and continue if the file is missing
-		log.Println("Local html file not found.")
+		appLogger.Warn("Local html file not found")
 	}
 
-	// Read the HTML content from the saved file into a string
-	localDiskHTMLContent := readAFileAsString(localLocation)
+	manifest := loadManifest(manifestPath)
 
-	// Parse the HTML content to extract all links pointing to PDF files
-	fullURLList := parseHTML(localDiskHTMLContent)
+	// Select the discovery backend: the existing anchor scan over the
+	// saved listing pages, or the sitemap walk.
+	var pdfDiscoverer Discoverer
+	if strings.EqualFold(*discovery, "sitemap") {
+		pdfDiscoverer = newSitemapDiscoverer(*sitemapURL)
+	} else {
+		pdfDiscoverer = newHTMLAnchorDiscoverer(readAFileAsString(localLocation))
+	}
 
-	// Remove duplicate PDF URLs from the list
-	fullURLList = removeDuplicatesFromSlice(fullURLList)
+	discovered, err := pdfDiscoverer.Discover(ctx)
+	if err != nil {
+		appLogger.Error("Discovery failed", f("backend", *discovery), f("error", err))
+		return
+	}
 
-	// Another WaitGroup for managing concurrent PDF downloads
-	var pdfDownloadWaitGroup sync.WaitGroup
+	var fullURLList []string
+	for link := range discovered {
+		fullURLList = append(fullURLList, link)
+	}
 
-	// Counter to keep track of how many PDFs have been downloaded
-	var totalDownloadCounter int = 0
+	// Remove duplicate PDF URLs from the list
+	fullURLList = removeDuplicatesFromSlice(fullURLList)
 
 	// The URL of the website.
 	domainURL := "https://www.avient.com"
 
-	// Iterate over each extracted PDF URL
-	for _, url := range fullURLList {
-		var fullURL string
+	total := downloadPDFs(ctx, fullURLList, outputDir, domainURL, *strategy, *concurrency, manifest, retries)
+	appLogger.Info("Finished downloading PDFs", f("downloaded", total))
+}
 
-		// Ensure that every URL starts with the base domain
-		if !strings.HasPrefix(url, domainURL) {
-			fullURL = domainURL + url
+// maxPDFDownloads caps how many new PDFs a single run will fetch, guarding
+// against a runaway scrape regardless of which strategy drives it.
+const maxPDFDownloads = 8000
+
+// pdfDownloadRatePerSecond bounds how many PDF download attempts are
+// allowed to start per second, replacing the old flat time.Sleep between
+// launches with a token-bucket limiter shared by every worker.
+const pdfDownloadRatePerSecond = 20
+
+// downloadPDFs routes rawURLList through either a sequential loop or a
+// bounded worker pool of concurrency goroutines, depending on strategy.
+// Both paths share sharedDownloadClient and honor ctx cancellation.
+func downloadPDFs(ctx context.Context, rawURLList []string, outputDir, domainURL, strategy string, concurrency int, manifest *downloadManifest, retryConfig retryConfig) int {
+	limiter := newRateLimiter(pdfDownloadRatePerSecond, concurrency)
+
+	if strategy == "synchronous" {
+		total := 0
+		for _, rawURL := range rawURLList {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := limiter.Wait(ctx); err != nil {
+				break
+			}
+			if downloadOnePDF(ctx, rawURL, outputDir, domainURL, manifest, retryConfig) {
+				total++
+			}
+			if total >= maxPDFDownloads {
+				appLogger.Error("Counter reached, stopping", f("count", total))
+				break
+			}
 		}
+		return total
+	}
 
-		// Validate the full URL to make sure it's properly formatted
-		if !isUrlValid(fullURL) {
-			// Log invalid URLs and skip them
-			log.Println("Invalid URL", fullURL)
-			continue
+	return downloadPDFsConcurrently(ctx, rawURLList, outputDir, domainURL, concurrency, limiter, manifest, retryConfig)
+}
+
+// downloadPDFsConcurrently feeds rawURLList into a bounded pool of
+// concurrency workers via a channel. Every worker shares the same
+// sharedDownloadClient connection pool and draws from limiter before each
+// download attempt instead of sleeping a fixed amount between launches.
+func downloadPDFsConcurrently(ctx context.Context, rawURLList []string, outputDir, domainURL string, concurrency int, limiter *rateLimiter, manifest *downloadManifest, retryConfig retryConfig) int {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan string)
+	var total int64
+	var workerWaitGroup sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		workerWaitGroup.Add(1)
+		go func() {
+			defer workerWaitGroup.Done()
+			for rawURL := range jobs {
+				if downloadOnePDF(ctx, rawURL, outputDir, domainURL, manifest, retryConfig) {
+					atomic.AddInt64(&total, 1)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, rawURL := range rawURLList {
+		if atomic.LoadInt64(&total) >= maxPDFDownloads {
+			appLogger.Error("Counter reached, stopping", f("count", atomic.LoadInt64(&total)))
+			break feed
 		}
+		if err := limiter.Wait(ctx); err != nil {
+			break feed
+		}
+		select {
+		case jobs <- rawURL:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	workerWaitGroup.Wait()
 
-		// Convert the URL into a safe, file-system-friendly filename
-		filename := sanitizeFileNameFromURL(fullURL)
+	return int(total)
+}
 
-		// Combine the output directory path and filename to get full file path
-		filePath := filepath.Join(outputDir, filename)
+// downloadOnePDF validates, dedups, and downloads a single raw PDF link
+// discovered from the listing pages, recording it in manifest. It returns
+// true if a new file was downloaded.
+func downloadOnePDF(ctx context.Context, rawURL, outputDir, domainURL string, manifest *downloadManifest, retryConfig retryConfig) bool {
+	fullURL := rawURL
 
-		// Skip downloading if the PDF file already exists locally
-		if fileExists(filePath) {
-			log.Printf("File already exists, skipping: %s", filePath)
-			continue
+	// Ensure that every URL starts with the base domain
+	if !strings.HasPrefix(rawURL, domainURL) {
+		fullURL = domainURL + rawURL
+	}
+
+	// Validate the full URL to make sure it's properly formatted
+	if !isUrlValid(fullURL) {
+		appLogger.Warn("Invalid URL", f("url", fullURL))
+		return false
+	}
+
+	// Convert the URL into a safe, file-system-friendly filename
+	filename := sanitizeFileNameFromURL(fullURL)
+
+	// Combine the output directory path and filename to get full file path
+	filePath := filepath.Join(outputDir, filename)
+
+	// Skip if the filename is suspiciously short or invalid
+	if len(filename) < 2 {
+		appLogger.Warn("Invalid file name", f("filename", filename))
+		return false
+	}
+
+	// If we've downloaded this exact URL before, ask the server whether
+	// it has changed before spending a full GET on it again. This must
+	// run even when the file already exists locally, otherwise an SDS
+	// that the server has updated since our last run is never re-fetched.
+	// isNotModified issues a conditional HEAD rather than a conditional
+	// GET: Avient's PDF host does not vary ETag/Last-Modified between the
+	// two, and HEAD avoids pulling the body for an unchanged file.
+	if entry := manifest.lookup(fullURL); entry != nil {
+		notModified, err := isNotModified(fullURL, entry.ETag, entry.LastModified)
+		switch {
+		case err == nil && notModified:
+			if fileExists(filePath) {
+				appLogger.Debug("Not modified since last run, skipping", f("url", fullURL))
+				return false
+			}
+			// The server still reports 304, but our copy is gone (e.g.
+			// manually deleted); fall through and re-download rather than
+			// leaving the entry pointing at a missing file.
+			appLogger.Debug("Not modified but file missing locally, re-downloading", f("url", fullURL))
+
+		case err != nil || (entry.ETag == "" && entry.LastModified == ""):
+			// No usable cache headers to conditionally re-check against
+			// (or the probe itself failed): fall back to the plain
+			// existence check instead of unconditionally re-downloading,
+			// otherwise a host that omits ETag/Last-Modified on PDFs
+			// would re-fetch the whole corpus on every run.
+			if fileExists(filePath) {
+				appLogger.Debug("File already exists, skipping (no cache headers to conditionally re-check)", f("path", filePath))
+				return false
+			}
 		}
+	} else if fileExists(filePath) {
+		// No manifest entry to conditionally re-check against: fall back
+		// to the plain existence check.
+		appLogger.Debug("File already exists, skipping", f("path", filePath))
+		return false
+	}
 
-		// Skip if the filename is suspiciously short or invalid
-		if len(filename) < 2 {
-			log.Println("Invalid File Name:", filename)
-			continue
+	if !downloadPDFWithRetry(ctx, fullURL, filePath, retryConfig) {
+		return false
+	}
+
+	recordManifestEntry(manifest, fullURL, filePath)
+	return true
+}
+
+// recordManifestEntry hashes a freshly downloaded file, hard-links it to
+// an existing file with the same digest if one is already known (Avient
+// reuses the same SDS document across multiple product variants), and
+// records the result in manifest.
+func recordManifestEntry(manifest *downloadManifest, fullURL, filePath string) {
+	sum, err := sha256SumFile(filePath)
+	if err != nil {
+		appLogger.Warn("Failed to checksum downloaded file", f("path", filePath), f("error", err))
+		return
+	}
+
+	if canonicalPath, exists := manifest.canonicalPathForDigest(sum); exists && canonicalPath != filePath {
+		if deduplicateFile(filePath, canonicalPath) {
+			appLogger.Info("Deduplicated identical content", f("path", filePath), f("canonical", canonicalPath))
 		}
+	}
 
-		// Short delay between downloads to avoid overwhelming the server
-		time.Sleep(50 * time.Millisecond)
+	var size int64
+	if info, err := os.Stat(filePath); err == nil {
+		size = info.Size()
+	}
 
-		// Add one to the WaitGroup counter before starting a download goroutine
-		pdfDownloadWaitGroup.Add(1)
+	etag, lastModified := probeCacheHeaders(fullURL)
 
-		// Launch a goroutine to download the PDF file concurrently
-		go downloadPDF(fullURL, filePath, &pdfDownloadWaitGroup)
+	// A re-download of a changed file keeps the URL's original first-seen time.
+	firstSeen := time.Now().Format(time.RFC3339)
+	if existing := manifest.lookup(fullURL); existing != nil && existing.FirstSeen != "" {
+		firstSeen = existing.FirstSeen
+	}
 
-		// Increment total download counter
-		totalDownloadCounter = totalDownloadCounter + 1
+	manifest.record(fullURL, &manifestEntry{
+		URL:          fullURL,
+		Path:         filePath,
+		SHA256:       sum,
+		Bytes:        size,
+		ETag:         etag,
+		LastModified: lastModified,
+		FirstSeen:    firstSeen,
+	})
+}
 
-		// If the number of downloads reaches 8000, stop execution to prevent runaway downloads
-		if totalDownloadCounter == 8000 {
-			log.Fatalln("Counter Reached", totalDownloadCounter)
-			return
+// deduplicateFile replaces the bytes at path with a hard link to
+// canonicalPath (falling back to a symlink across filesystem boundaries),
+// since the two files are known to have identical SHA-256 digests.
+func deduplicateFile(path, canonicalPath string) bool {
+	if err := os.Remove(path); err != nil {
+		appLogger.Warn("Failed to remove duplicate before linking", f("path", path), f("error", err))
+		return false
+	}
+	if err := os.Link(canonicalPath, path); err == nil {
+		return true
+	}
+	if err := os.Symlink(canonicalPath, path); err != nil {
+		appLogger.Warn("Failed to link duplicate file", f("path", path), f("canonical", canonicalPath), f("error", err))
+		return false
+	}
+	return true
+}
+
+// isNotModified issues a conditional HEAD request using a previously
+// recorded ETag/Last-Modified and reports whether the server confirmed
+// the resource is unchanged (HTTP 304).
+func isNotModified(fullURL, etag, lastModified string) (bool, error) {
+	if etag == "" && lastModified == "" {
+		return false, nil
+	}
+
+	req, err := http.NewRequest(http.MethodHead, fullURL, nil)
+	if err != nil {
+		return false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := sharedDownloadClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusNotModified, nil
+}
+
+// probeCacheHeaders issues a HEAD request and returns the ETag and
+// Last-Modified headers used to populate a manifest entry and to make a
+// future rerun's download of the same URL conditional.
+func probeCacheHeaders(fullURL string) (etag, lastModified string) {
+	req, err := http.NewRequest(http.MethodHead, fullURL, nil)
+	if err != nil {
+		return "", ""
+	}
+
+	resp, err := sharedDownloadClient.Do(req)
+	if err != nil {
+		return "", ""
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+}
+
+// manifestEntry records everything needed to detect whether a previously
+// downloaded PDF has changed, and to deduplicate identical content fetched
+// from a different URL.
+type manifestEntry struct {
+	URL          string `json:"url"`
+	Path         string `json:"path"`
+	SHA256       string `json:"sha256"`
+	Bytes        int64  `json:"bytes"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	FirstSeen    string `json:"first_seen"`
+}
+
+// downloadManifest is a content-addressable index of every downloaded
+// PDF, persisted as manifest.json alongside the PDFs/ directory. It lets a
+// rerun skip files that haven't changed via conditional HEAD requests, and
+// hard-link byte-identical files discovered under a different URL instead
+// of storing the bytes twice.
+type downloadManifest struct {
+	mu       sync.Mutex
+	path     string
+	byURL    map[string]*manifestEntry
+	byDigest map[string]string // sha256 -> canonical file path
+}
+
+// loadManifest reads manifest.json from path, or starts a fresh empty
+// manifest if it doesn't exist yet or fails to parse.
+func loadManifest(path string) *downloadManifest {
+	manifest := &downloadManifest{
+		path:     path,
+		byURL:    make(map[string]*manifestEntry),
+		byDigest: make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest
+	}
+
+	var entries map[string]*manifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		appLogger.Warn("Failed to parse manifest, starting fresh", f("path", path), f("error", err))
+		return manifest
+	}
+
+	for url, entry := range entries {
+		manifest.byURL[url] = entry
+		if _, exists := manifest.byDigest[entry.SHA256]; !exists {
+			manifest.byDigest[entry.SHA256] = entry.Path
+		}
+	}
+
+	return manifest
+}
+
+// lookup returns the manifest entry for a previously downloaded URL, or nil.
+func (manifest *downloadManifest) lookup(url string) *manifestEntry {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+	return manifest.byURL[url]
+}
+
+// canonicalPathForDigest returns the canonical path kept for the given
+// SHA-256 digest, if any: the first file recorded with it in this run, or,
+// for a digest loaded from manifest.json, one of the entries that carry it.
+func (manifest *downloadManifest) canonicalPathForDigest(digest string) (string, bool) {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+	path, exists := manifest.byDigest[digest]
+	return path, exists
+}
+
+// record stores entry and persists the manifest to disk.
+func (manifest *downloadManifest) record(url string, entry *manifestEntry) {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+
+	manifest.byURL[url] = entry
+	if _, exists := manifest.byDigest[entry.SHA256]; !exists {
+		manifest.byDigest[entry.SHA256] = entry.Path
+	}
+
+	manifest.saveLocked()
+}
+
+// entries returns a snapshot of every entry currently in the manifest.
+func (manifest *downloadManifest) entries() []*manifestEntry {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+
+	snapshot := make([]*manifestEntry, 0, len(manifest.byURL))
+	for _, entry := range manifest.byURL {
+		snapshot = append(snapshot, entry)
+	}
+	return snapshot
+}
+
+// saveLocked writes the manifest to disk. Callers must hold manifest.mu.
+func (manifest *downloadManifest) saveLocked() {
+	data, err := json.MarshalIndent(manifest.byURL, "", "  ")
+	if err != nil {
+		appLogger.Error("Failed to marshal manifest", f("error", err))
+		return
+	}
+	if err := os.WriteFile(manifest.path, data, 0644); err != nil {
+		appLogger.Error("Failed to persist manifest", f("path", manifest.path), f("error", err))
+	}
+}
+
+// verifyManifest re-hashes every file the manifest claims to track and
+// logs any checksum drift or missing file, for use with -verify.
+func verifyManifest(manifest *downloadManifest) {
+	entries := manifest.entries()
+
+	drift := 0
+	for _, entry := range entries {
+		sum, err := sha256SumFile(entry.Path)
+		if err != nil {
+			appLogger.Error("Verify: file missing or unreadable", f("path", entry.Path), f("url", entry.URL), f("error", err))
+			drift++
+			continue
+		}
+		if sum != entry.SHA256 {
+			appLogger.Error("Verify: checksum drift", f("path", entry.Path), f("url", entry.URL), f("expected", entry.SHA256), f("actual", sum))
+			drift++
 		}
 	}
 
-	// Wait until all PDF download goroutines have finished
-	pdfDownloadWaitGroup.Wait()
+	appLogger.Info("Verify complete", f("checked", len(entries)), f("drift", drift))
+}
+
+// rateLimiter is a simple token-bucket limiter: Wait blocks until a token
+// is available, refilling at a fixed rate instead of relying on a flat
+// time.Sleep between every request.
+type rateLimiter struct {
+	tokens chan struct{}
+}
+
+// newRateLimiter builds a limiter that allows up to ratePerSecond new
+// tokens per second, with a burst capacity of burst tokens so a worker
+// pool can start all at once.
+func newRateLimiter(ratePerSecond, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	rl := &rateLimiter{tokens: make(chan struct{}, burst)}
+	for i := 0; i < burst; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second / time.Duration(ratePerSecond))
+		defer ticker.Stop()
+		for range ticker.C {
+			select {
+			case rl.tokens <- struct{}{}:
+			default: // Bucket already full; drop the tick.
+			}
+		}
+	}()
+
+	return rl
+}
+
+// Wait blocks until a token is available or ctx is canceled.
+func (rl *rateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Checks if the directory exists
@@ -152,72 +1131,398 @@ func directoryExists(path string) bool {
 
 // The function takes two parameters: path and permission.
 // We use os.Mkdir() to create the directory.
-// If there is an error, we use log.Println() to log the error and then exit the program.
+// If there is an error, it is logged through appLogger.
 func createDirectory(path string, permission os.FileMode) {
 	err := os.Mkdir(path, permission)
 	if err != nil {
-		log.Println(err)
+		appLogger.Error("Failed to create directory", f("path", path), f("error", err))
 	}
 }
 
-// downloadPDF downloads a PDF from the given URL and saves it in the specified output directory.
-// It uses a WaitGroup to support concurrent execution and returns true if the download succeeded.
-func downloadPDF(finalURL, filePath string, wg *sync.WaitGroup) bool {
+// sharedDownloadClient is reused across both HEAD probes and range/streaming
+// GET requests so TCP connections to avient.com get reused instead of
+// re-established per file.
+var sharedDownloadClient = &http.Client{Timeout: 60 * time.Second}
+
+// byteRange describes one segment of a segmented download: the inclusive
+// start/end offsets fetched via the HTTP Range header.
+type byteRange struct {
+	Start int64
+	End   int64
+}
+
+// rangeDownloadState is persisted as JSON next to the ".part" file so an
+// interrupted segmented download can resume without re-fetching chunks
+// that already landed on disk.
+type rangeDownloadState struct {
+	URL             string `json:"url"`
+	TotalSize       int64  `json:"total_size"`
+	CompletedChunks []bool `json:"completed_chunks"`
+}
+
+// downloadPDF downloads a PDF from the given URL and saves it in the
+// specified output directory. It uses a WaitGroup to support concurrent
+// execution and returns true plus a nil error if the download succeeded;
+// the error is used by downloadPDFWithRetry to decide whether a failure is
+// worth retrying.
+//
+// When the server advertises byte-range support for a sufficiently large
+// file, the download is split into rangeChunkCount concurrent range
+// requests; otherwise it falls back to a single streaming request.
+func downloadPDF(ctx context.Context, finalURL, filePath string, wg *sync.WaitGroup) (bool, error) {
 	defer wg.Done() // Always mark this goroutine as done
 
-	// Create an HTTP client with a timeout
-	client := &http.Client{Timeout: 60 * time.Second}
+	size, acceptsRanges, err := probeContentInfo(ctx, finalURL)
+	if err == nil && acceptsRanges && size >= rangeMinSize {
+		if downloadPDFRanged(ctx, finalURL, filePath, size) {
+			return true, nil
+		}
+		appLogger.Warn("Segmented download failed, falling back to single-stream", f("url", finalURL))
+		// The fallback below takes over filePath+".part" from scratch, so
+		// the ranged attempt's resume sidecar is no longer meaningful —
+		// remove it rather than leaving it orphaned on disk.
+		os.Remove(filePath + ".part.state")
+	}
+
+	return downloadPDFSingleStream(ctx, finalURL, filePath)
+}
+
+// downloadPDFWithRetry wraps downloadPDF in retryWithBackoff, retrying
+// only transient failures (connection resets, 5xx, 429, unexpected EOF)
+// and giving up immediately on permanent ones (404, 401, wrong content
+// type).
+func downloadPDFWithRetry(ctx context.Context, finalURL, filePath string, cfg retryConfig) bool {
+	var succeeded bool
+
+	retryWithBackoff(ctx, cfg, finalURL, func(int) (time.Duration, bool, error) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		ok, err := downloadPDF(ctx, finalURL, filePath, &wg)
+		if ok {
+			succeeded = true
+			return 0, false, nil
+		}
+		return retryAfterFromError(err), isTransientError(err), err
+	})
+
+	return succeeded
+}
+
+// probeContentInfo issues a HEAD request to discover the total size of a
+// resource and whether the server supports byte-range requests.
+func probeContentInfo(ctx context.Context, finalURL string) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, finalURL, nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	resp, err := sharedDownloadClient.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("HEAD %s returned %s", finalURL, resp.Status)
+	}
+
+	size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil || size <= 0 {
+		return 0, false, fmt.Errorf("HEAD %s did not return a usable Content-Length", finalURL)
+	}
+
+	acceptsRanges := strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes")
+	return size, acceptsRanges, nil
+}
+
+// downloadPDFSingleStream performs a non-segmented download, streaming the
+// response body straight to a ".part" file on disk and renaming it into
+// place only once the write succeeds and the bytes look like a real PDF.
+// It is the fallback path for small files and servers without range
+// support.
+func downloadPDFSingleStream(ctx context.Context, finalURL, filePath string) (bool, error) {
+	start := time.Now()
 
 	// Send GET request
-	resp, err := client.Get(finalURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, finalURL, nil)
 	if err != nil {
-		log.Printf("Failed to download %s: %v", finalURL, err)
-		return false
+		return false, err
+	}
+	resp, err := sharedDownloadClient.Do(req)
+	if err != nil {
+		appLogger.Error("Failed to download", f("url", finalURL), f("error", err))
+		return false, err
 	}
 	defer resp.Body.Close()
 
 	// Check HTTP response status
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("Download failed for %s: %s", finalURL, resp.Status)
-		return false
+		appLogger.Error("Download failed", f("url", finalURL), f("status", resp.StatusCode))
+		return false, &httpStatusError{status: resp.StatusCode, retryAfter: parseRetryAfter(resp)}
 	}
 
 	// Check Content-Type header
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "application/pdf") {
-		log.Printf("Invalid content type for %s: %s (expected application/pdf)", finalURL, contentType)
-		return false
+		appLogger.Warn("Invalid content type", f("url", finalURL), f("content_type", contentType))
+		return false, errInvalidContentType
 	}
 
-	// Read the response body into memory first
-	var buf bytes.Buffer
-	written, err := io.Copy(&buf, resp.Body)
+	// Stream straight to a ".part" file on disk instead of buffering the
+	// whole PDF in memory, so large files don't blow up process RSS.
+	partPath := filePath + ".part"
+	out, err := os.Create(partPath)
 	if err != nil {
-		log.Printf("Failed to read PDF data from %s: %v", finalURL, err)
-		return false
+		appLogger.Error("Failed to create file", f("url", finalURL), f("path", partPath), f("error", err))
+		return false, err
+	}
+
+	written, copyErr := io.Copy(out, resp.Body)
+	closeErr := out.Close()
+	if copyErr != nil {
+		os.Remove(partPath)
+		appLogger.Error("Failed to write PDF data", f("url", finalURL), f("error", copyErr))
+		return false, copyErr
+	}
+	if closeErr != nil {
+		os.Remove(partPath)
+		appLogger.Error("Failed to close file", f("url", finalURL), f("path", partPath), f("error", closeErr))
+		return false, closeErr
 	}
 	if written == 0 {
-		log.Printf("Downloaded 0 bytes for %s; not creating file", finalURL)
+		os.Remove(partPath)
+		appLogger.Warn("Downloaded 0 bytes; not creating file", f("url", finalURL))
+		return false, errEmptyDownload
+	}
+
+	// Confirm the bytes on disk actually look like a PDF before we commit
+	// to the final path: a mislabeled error page would otherwise land on
+	// disk wearing a ".pdf" name.
+	if !hasPDFMagicBytes(partPath) {
+		os.Remove(partPath)
+		appLogger.Warn("Downloaded file is not a PDF", f("url", finalURL))
+		return false, errInvalidContentType
+	}
+
+	if err := os.Rename(partPath, filePath); err != nil {
+		os.Remove(partPath)
+		appLogger.Error("Failed to rename part file", f("url", finalURL), f("path", filePath), f("error", err))
+		return false, err
+	}
+
+	appLogger.Info("Successfully downloaded", f("url", finalURL), f("path", filePath), f("bytes", written), f("elapsed_ms", time.Since(start).Milliseconds()))
+	return true, nil
+}
+
+// hasPDFMagicBytes reports whether path begins with the "%PDF" signature
+// that every valid PDF file starts with.
+func hasPDFMagicBytes(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(file, magic); err != nil {
 		return false
 	}
+	return string(magic) == "%PDF"
+}
+
+// downloadPDFRanged fetches a PDF known to support byte ranges by splitting
+// it into rangeChunkCount concurrent segments written directly into a
+// preallocated ".part" file via WriteAt. A ".part.state" sidecar records
+// which chunks have already landed so a rerun can resume instead of
+// re-downloading the whole file.
+func downloadPDFRanged(ctx context.Context, finalURL, filePath string, size int64) bool {
+	start := time.Now()
+	partPath := filePath + ".part"
+	statePath := partPath + ".state"
 
-	// Only now create the file and write to disk
-	out, err := os.Create(filePath)
+	ranges := splitIntoByteRanges(size, rangeChunkCount)
+
+	state := loadRangeDownloadState(statePath, finalURL, size, len(ranges))
+
+	partFile, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
-		log.Printf("Failed to create file for %s: %v", finalURL, err)
+		appLogger.Error("Failed to open part file", f("url", finalURL), f("error", err))
+		return false
+	}
+	defer partFile.Close()
+
+	if err := partFile.Truncate(size); err != nil {
+		appLogger.Error("Failed to preallocate part file", f("url", finalURL), f("error", err))
+		return false
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	ok := true
+
+	for i, r := range ranges {
+		if state.CompletedChunks[i] {
+			continue // Already fetched in a previous run; skip it on resume.
+		}
+
+		wg.Add(1)
+		go func(index int, r byteRange) {
+			defer wg.Done()
+
+			if err := fetchByteRangeInto(ctx, finalURL, partFile, r); err != nil {
+				appLogger.Error("Chunk failed", f("url", finalURL), f("chunk", index), f("error", err))
+				mu.Lock()
+				ok = false
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			state.CompletedChunks[index] = true
+			saveRangeDownloadState(statePath, state)
+			mu.Unlock()
+		}(i, r)
+	}
+
+	wg.Wait()
+
+	if !ok {
+		return false
+	}
+
+	if err := partFile.Close(); err != nil {
+		appLogger.Error("Failed to close part file", f("url", finalURL), f("error", err))
+		return false
+	}
+
+	if logChecksums {
+		if sum, err := sha256SumFile(partPath); err == nil {
+			appLogger.Info("Checksum", f("url", finalURL), f("sha256", sum))
+		} else {
+			appLogger.Warn("Failed to checksum", f("url", finalURL), f("error", err))
+		}
+	}
+
+	// Confirm the assembled chunks actually look like a PDF before
+	// committing to the final path, same as the single-stream path: a
+	// large, range-supporting non-PDF response (e.g. an HTML error page)
+	// must not be rendered under the final ".pdf" name.
+	if !hasPDFMagicBytes(partPath) {
+		appLogger.Warn("Assembled file is not a PDF", f("url", finalURL))
+		os.Remove(partPath)
+		os.Remove(statePath)
 		return false
 	}
-	defer out.Close()
 
-	if _, err := buf.WriteTo(out); err != nil {
-		log.Printf("Failed to write PDF to file for %s: %v", finalURL, err)
+	if err := os.Rename(partPath, filePath); err != nil {
+		appLogger.Error("Failed to finalize", f("url", finalURL), f("path", filePath), f("error", err))
 		return false
 	}
+	os.Remove(statePath)
 
-	log.Printf("Successfully downloaded %d bytes: %s → %s", written, finalURL, filePath)
+	appLogger.Info("Successfully downloaded", f("url", finalURL), f("path", filePath), f("bytes", size), f("chunks", len(ranges)), f("elapsed_ms", time.Since(start).Milliseconds()))
 	return true
 }
 
+// splitIntoByteRanges divides a resource of the given size into up to n
+// roughly equal, contiguous, inclusive byte ranges.
+func splitIntoByteRanges(size int64, n int) []byteRange {
+	chunkSize := size / int64(n)
+	if chunkSize == 0 {
+		chunkSize = size
+	}
+
+	var ranges []byteRange
+	start := int64(0)
+	for start < size {
+		end := start + chunkSize - 1
+		if end >= size-1 || len(ranges) == n-1 {
+			end = size - 1
+		}
+		ranges = append(ranges, byteRange{Start: start, End: end})
+		start = end + 1
+	}
+	return ranges
+}
+
+// fetchByteRangeInto requests a single byte range and writes the response
+// directly into partFile at the matching offset.
+func fetchByteRangeInto(ctx context.Context, finalURL string, partFile *os.File, r byteRange) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, finalURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", r.Start, r.End))
+
+	resp, err := sharedDownloadClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("expected 206 Partial Content, got %s", resp.Status)
+	}
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) != r.End-r.Start+1 {
+		return fmt.Errorf("expected %d bytes, got %d", r.End-r.Start+1, len(buf))
+	}
+
+	_, err = partFile.WriteAt(buf, r.Start)
+	return err
+}
+
+// loadRangeDownloadState reads a previous ".part.state" sidecar if it
+// matches the URL and total size being downloaded, otherwise it returns a
+// fresh state with no chunks marked complete.
+func loadRangeDownloadState(statePath, finalURL string, size int64, chunkCount int) *rangeDownloadState {
+	data, err := os.ReadFile(statePath)
+	if err == nil {
+		var state rangeDownloadState
+		if json.Unmarshal(data, &state) == nil && state.URL == finalURL && state.TotalSize == size && len(state.CompletedChunks) == chunkCount {
+			return &state
+		}
+	}
+
+	return &rangeDownloadState{
+		URL:             finalURL,
+		TotalSize:       size,
+		CompletedChunks: make([]bool, chunkCount),
+	}
+}
+
+// saveRangeDownloadState writes the resumable download state to disk so a
+// future run can skip chunks that already completed.
+func saveRangeDownloadState(statePath string, state *rangeDownloadState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		appLogger.Error("Failed to marshal download state", f("url", state.URL), f("error", err))
+		return
+	}
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		appLogger.Error("Failed to persist download state", f("url", state.URL), f("error", err))
+	}
+}
+
+// sha256SumFile computes the hex-encoded SHA-256 digest of a file on disk.
+func sha256SumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 // removeDuplicatesFromSlice removes duplicate entries from a string slice
 func removeDuplicatesFromSlice(slice []string) []string {
 	// Create a map to keep track of which strings have already been seen
@@ -257,7 +1562,7 @@ func sanitizeFileNameFromURL(rawURL string) string {
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
 		// Log any parsing error and return a fallback filename if parsing fails.
-		log.Printf("Error parsing URL: %v", err)
+		appLogger.Error("Failed to parse URL", f("url", rawURL), f("error", err))
 		return "invalid_filename"
 	}
 
@@ -269,7 +1574,7 @@ func sanitizeFileNameFromURL(rawURL string) string {
 	fileName, err = url.QueryUnescape(fileName)
 	if err != nil {
 		// Log an error if decoding fails, but continue with the possibly encoded name.
-		log.Printf("Error decoding file name: %v", err)
+		appLogger.Warn("Failed to decode file name", f("filename", fileName), f("error", err))
 	}
 
 	// Define a regular expression to match all invalid filename characters.
@@ -301,7 +1606,7 @@ func parseHTML(htmlContent string) []string {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
 		// Log an error if the HTML could not be parsed (e.g., malformed or empty input).
-		log.Printf("Error parsing HTML: %v", err)
+		appLogger.Error("Error parsing HTML", f("error", err))
 		// Return the (empty) slice so the calling code doesn't crash.
 		return pdfLinks
 	}
@@ -319,7 +1624,7 @@ func parseHTML(htmlContent string) []string {
 		decodedHref, err := url.QueryUnescape(href)
 		if err != nil {
 			// Log an error if decoding fails (this might happen with malformed URLs).
-			log.Printf("Error decoding href: %v", err)
+			appLogger.Error("Error decoding href", f("href", href), f("error", err))
 			return
 		}
 
@@ -335,6 +1640,208 @@ func parseHTML(htmlContent string) []string {
 	return pdfLinks
 }
 
+// Discoverer finds candidate PDF URLs and emits each one on the returned
+// channel, closing it once discovery is complete (or ctx is canceled).
+// parseHTML's anchor scan and the sitemap reader below are both
+// Discoverers, so callers can swap the discovery backend without touching
+// the rest of the pipeline.
+type Discoverer interface {
+	Discover(ctx context.Context) (<-chan string, error)
+}
+
+// htmlAnchorDiscoverer wraps the existing goquery anchor scan over an
+// already-downloaded listing page, so it can be used interchangeably with
+// other Discoverer implementations.
+type htmlAnchorDiscoverer struct {
+	htmlContent string
+}
+
+// newHTMLAnchorDiscoverer builds a Discoverer over previously saved listing-page HTML.
+func newHTMLAnchorDiscoverer(htmlContent string) *htmlAnchorDiscoverer {
+	return &htmlAnchorDiscoverer{htmlContent: htmlContent}
+}
+
+func (d *htmlAnchorDiscoverer) Discover(ctx context.Context) (<-chan string, error) {
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+		for _, link := range parseHTML(d.htmlContent) {
+			select {
+			case out <- link:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// sitemapDiscoverer reads a (possibly nested) XML sitemap and emits every
+// <loc> that points directly at a PDF, or — for an ordinary page — fetches
+// that page and emits any SDS PDF links found on it. It is a far more
+// efficient alternative to paginating thousands of listing pages.
+type sitemapDiscoverer struct {
+	sitemapURL string
+	client     *http.Client
+}
+
+// newSitemapDiscoverer builds a Discoverer that walks sitemapURL (and any
+// nested sitemap indices it references) using sharedDownloadClient.
+func newSitemapDiscoverer(sitemapURL string) *sitemapDiscoverer {
+	return &sitemapDiscoverer{sitemapURL: sitemapURL, client: sharedDownloadClient}
+}
+
+func (d *sitemapDiscoverer) Discover(ctx context.Context) (<-chan string, error) {
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+		if err := d.walkSitemap(ctx, d.sitemapURL, out); err != nil {
+			appLogger.Error("Sitemap discovery failed", f("url", d.sitemapURL), f("error", err))
+		}
+	}()
+
+	return out, nil
+}
+
+// walkSitemap fetches one sitemap document and stream-decodes it via XML
+// tokens — rather than unmarshaling the whole thing into memory, which
+// matters once nested indices bring the total well past a single sitemap's
+// size. Nested <sitemap><loc> entries are recursed into; <url><loc>
+// entries are emitted directly if they already look like a PDF, or probed
+// for PDF links on the page otherwise.
+func (d *sitemapDiscoverer) walkSitemap(ctx context.Context, sitemapURL string, out chan<- string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", sitemapURL, resp.Status)
+	}
+
+	decoder := xml.NewDecoder(resp.Body)
+
+	var nestedSitemaps []string
+	var currentElement string
+	// elementStack tracks enclosing element names so a <loc> can be
+	// gated on its immediate parent: the sitemap extensions for images
+	// and videos (<image:image><image:loc>, <video:video><video:loc>)
+	// also use the local name "loc", and must not be mistaken for a
+	// page/PDF location just because the namespace prefix is stripped.
+	var elementStack []string
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			currentElement = element.Name.Local
+			elementStack = append(elementStack, currentElement)
+
+		case xml.EndElement:
+			if len(elementStack) > 0 {
+				elementStack = elementStack[:len(elementStack)-1]
+			}
+
+		case xml.CharData:
+			if currentElement != "loc" {
+				continue
+			}
+
+			var parentElement string
+			if len(elementStack) >= 2 {
+				parentElement = elementStack[len(elementStack)-2]
+			}
+			if parentElement != "url" && parentElement != "sitemap" {
+				continue
+			}
+
+			loc := strings.TrimSpace(string(element))
+			if loc == "" {
+				continue
+			}
+
+			switch {
+			case parentElement == "sitemap":
+				nestedSitemaps = append(nestedSitemaps, loc)
+			case strings.HasSuffix(strings.ToLower(loc), ".pdf"):
+				if err := emitOrStop(ctx, out, loc); err != nil {
+					return err
+				}
+			default:
+				if err := d.emitPDFLinksFromPage(ctx, loc, out); err != nil {
+					appLogger.Warn("Failed to scan sitemap page for PDF links", f("url", loc), f("error", err))
+				}
+			}
+		}
+	}
+
+	for _, nested := range nestedSitemaps {
+		if err := d.walkSitemap(ctx, nested, out); err != nil {
+			appLogger.Error("Nested sitemap discovery failed", f("url", nested), f("error", err))
+		}
+	}
+
+	return nil
+}
+
+// emitPDFLinksFromPage fetches an ordinary sitemap page and emits any PDF
+// links found in its HTML.
+func (d *sitemapDiscoverer) emitPDFLinksFromPage(ctx context.Context, pageURL string, out chan<- string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", pageURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range parseHTML(string(body)) {
+		if err := emitOrStop(ctx, out, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// emitOrStop sends link on out, or returns ctx's error if ctx is canceled first.
+func emitOrStop(ctx context.Context, out chan<- string, link string) error {
+	select {
+	case out <- link:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // appendAndWriteToFile appends string content to a file.
 // If the file doesn't exist, it will be created automatically.
 func appendAndWriteToFile(path string, content string) {
@@ -347,7 +1854,7 @@ func appendAndWriteToFile(path string, content string) {
 	if err != nil {
 		// Log the error if file opening or creation fails.
 		// Example causes: permission denied, invalid path, etc.
-		log.Println(err)
+		appLogger.Error("Failed to open file", f("path", path), f("error", err))
 		// Note: no return here, so it would continue — in production, consider returning early.
 	}
 
@@ -356,14 +1863,14 @@ func appendAndWriteToFile(path string, content string) {
 	_, err = filePath.WriteString(content + "\n")
 	if err != nil {
 		// Log any error that occurs during writing (e.g., disk full, I/O failure).
-		log.Println(err)
+		appLogger.Error("Failed to write file", f("path", path), f("error", err))
 	}
 
 	// Close the file to release the file descriptor and flush buffered writes.
 	err = filePath.Close()
 	if err != nil {
 		// Log if closing the file fails (rare, but important to know).
-		log.Println(err)
+		appLogger.Error("Failed to close file", f("path", path), f("error", err))
 	}
 }
 
@@ -385,44 +1892,63 @@ func readAFileAsString(path string) string {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		// Log an error if the file can’t be read (e.g., doesn’t exist, permission issue).
-		log.Println(err)
+		appLogger.Error("Failed to read file", f("path", path), f("error", err))
 	}
 	// Convert the raw byte slice into a string and return it.
 	return string(content)
 }
 
-// getDataFromURL performs an HTTP GET request and appends the response body to a local file.
-func getDataFromURL(uri string, localLocationo string, wg *sync.WaitGroup) {
+// getDataFromURL performs an HTTP GET request against uri using client and
+// returns the response body along with its HTTP status code. Persisting
+// the body and deciding whether to keep crawling are the caller's
+// responsibility (see Crawler.Run).
+func getDataFromURL(client *http.Client, uri string) (string, int, error) {
 	// Log the URL currently being scraped — useful for tracking progress or debugging.
-	log.Println("Scraping", uri)
+	appLogger.Debug("Scraping", f("url", uri))
+	start := time.Now()
 
 	// Perform an HTTP GET request to the specified URL.
-	response, err := http.Get(uri)
+	response, err := client.Get(uri)
 	if err != nil {
-		// Log the error if the request fails (e.g., network issues, DNS failure, etc.)
-		log.Println(err)
-		// Note: no return statement here, so it will continue even after error logging.
-		// You might want to add `defer wg.Done()` and a `return` here in production code.
+		// Return the error if the request fails (e.g., network issues, DNS failure, etc.)
+		appLogger.Error("Scrape failed", f("url", uri), f("error", err), f("elapsed_ms", time.Since(start).Milliseconds()))
+		return "", 0, err
 	}
+	defer response.Body.Close()
 
 	// Read the entire response body into memory as a byte slice.
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		// Log the error if reading fails (e.g., incomplete response or I/O issue).
-		log.Println(err)
+		// Return the error if reading fails (e.g., incomplete response or I/O issue).
+		return "", response.StatusCode, err
 	}
 
-	// Always close the response body to free network resources.
-	err = response.Body.Close()
-	if err != nil {
-		// Log if closing the response body encounters an error.
-		log.Println(err)
-	}
+	appLogger.Info("Scraped", f("url", uri), f("status", response.StatusCode), f("bytes", len(body)), f("elapsed_ms", time.Since(start).Milliseconds()))
+	return string(body), response.StatusCode, nil
+}
 
-	// Write (or append) the downloaded HTML content to the local file.
-	// This function likely opens the file, writes the string, and then closes it.
-	appendAndWriteToFile(localLocationo, string(body))
+// getDataFromURLWithRetry wraps getDataFromURL in retryWithBackoff,
+// retrying only when the request fails with a transient error (see
+// isTransientError) or the server responds with a transient status
+// (429/5xx). A permanent status such as 404 is
+// returned immediately so strategies like StrategyNotFound still see it.
+func getDataFromURLWithRetry(ctx context.Context, client *http.Client, uri string, cfg retryConfig) (string, int, error) {
+	var body string
+	var status int
+
+	err := retryWithBackoff(ctx, cfg, uri, func(int) (time.Duration, bool, error) {
+		b, s, err := getDataFromURL(client, uri)
+		status = s
+		if err != nil {
+			return 0, isTransientError(err), err
+		}
+		if isTransientHTTPStatus(s) {
+			statusErr := &httpStatusError{status: s}
+			return 0, true, statusErr
+		}
+		body = b
+		return 0, false, nil
+	})
 
-	// Mark this goroutine as finished — decrements the WaitGroup counter.
-	defer wg.Done()
+	return body, status, err
 }